@@ -0,0 +1,123 @@
+// Package cli разбирает флаги командной строки инструмента.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Options — разобранные флаги командной строки.
+type Options struct {
+	Domains          []string
+	DomainsFile      string
+	Wordlist         string
+	Resolvers        string
+	Threads          int
+	Timeout          time.Duration
+	Output           string
+	OutputJSON       bool
+	OutputCSV        bool
+	OutputDir        bool
+	Silent           bool
+	Stdin            bool
+	Passive          bool
+	Sources          string
+	ExcludeSources   string
+	Takeover         bool
+	NoWildcardFilter bool
+	RatePerResolver  float64
+	MaxRetries       int
+	Permute          bool
+	PermuteDepth     int
+}
+
+// shortAliases — однодефисные многобуквенные алиасы в духе subfinder/httpx
+// (-dL, -oJ, -oC, -oD), которые pflag как однорунный shorthand не умеет.
+// Разворачиваем их в длинную форму до разбора.
+var shortAliases = map[string]string{
+	"-dL": "--domains-file",
+	"-oJ": "--oJ",
+	"-oC": "--oC",
+	"-oD": "--oD",
+}
+
+func expandAliases(args []string) []string {
+	expanded := make([]string, 0, len(args))
+	for _, a := range args {
+		if long, ok := shortAliases[a]; ok {
+			expanded = append(expanded, long)
+			continue
+		}
+		expanded = append(expanded, a)
+	}
+	return expanded
+}
+
+// Parse разбирает os.Args[1:] в Options.
+func Parse() (Options, error) {
+	fs := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+
+	var o Options
+	fs.StringArrayVarP(&o.Domains, "domain", "d", nil, "домен для перебора (можно передать несколько раз)")
+	fs.StringVarP(&o.DomainsFile, "domains-file", "D", "domains.txt", "файл со списком доменов")
+	fs.StringVarP(&o.Wordlist, "wordlist", "w", "subdomains.txt", "файл со словарём поддоменов")
+	fs.StringVarP(&o.Resolvers, "resolvers", "r", "nameservers.txt", "файл со списком DNS-резолверов")
+	fs.IntVarP(&o.Threads, "threads", "t", runtime.NumCPU()*10, "число воркеров на домен")
+	fs.DurationVar(&o.Timeout, "timeout", 2*time.Second, "таймаут одного DNS-запроса")
+	fs.StringVarP(&o.Output, "output", "o", "", "файл для результатов (по умолчанию — каталог results/ для -oD, stdout для -oJ/-oC)")
+	fs.BoolVar(&o.OutputJSON, "oJ", false, "выводить результаты построчно в формате JSON")
+	fs.BoolVar(&o.OutputCSV, "oC", false, "выводить результаты в формате CSV")
+	fs.BoolVar(&o.OutputDir, "oD", false, "выводить результаты в каталог — по одному файлу на домен (поведение по умолчанию)")
+	fs.BoolVar(&o.Silent, "silent", false, "не выводить прогрессбар и служебные сообщения")
+	fs.BoolVar(&o.Stdin, "stdin", false, "читать слова словаря построчно из stdin вместо файла -w (к каждому дописывается .<домен>)")
+	fs.BoolVar(&o.Passive, "passive", false, "дополнительно собирать поддомены через пассивные источники (crt.sh, HackerTarget, ...)")
+	fs.StringVar(&o.Sources, "sources", "", "список источников пассивной разведки через запятую (по умолчанию — все)")
+	fs.StringVar(&o.ExcludeSources, "exclude-sources", "", "список источников пассивной разведки, которые нужно исключить, через запятую")
+	fs.BoolVar(&o.Takeover, "takeover", false, "проверять резолвящиеся поддомены на захватываемый CNAME (subdomain takeover)")
+	fs.BoolVar(&o.NoWildcardFilter, "no-wildcard-filter", false, "не определять и не отфильтровывать wildcard-DNS поддомены")
+	fs.Float64Var(&o.RatePerResolver, "rate-per-resolver", 0, "лимит запросов в секунду на резолвер (0 — без ограничения)")
+	fs.IntVar(&o.MaxRetries, "max-retries", 2, "сколько раз повторить запрос на другом резолвере при ошибке")
+	fs.BoolVar(&o.Permute, "permute", false, "после основного перебора сгенерировать и проверить altdns-пермутации найденных поддоменов")
+	fs.IntVar(&o.PermuteDepth, "permute-depth", 5, "максимальный числовой суффикс при переборе пермутаций (api1 -> api2..apiN)")
+
+	if err := fs.Parse(expandAliases(os.Args[1:])); err != nil {
+		return Options{}, err
+	}
+
+	if !o.OutputJSON && !o.OutputCSV {
+		o.OutputDir = true
+	}
+	if o.OutputDir && o.Output == "" {
+		o.Output = "results"
+	}
+
+	return o, nil
+}
+
+// SplitCSV разбирает список через запятую во флагах -sources/-exclude-sources.
+func SplitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Validate проверяет взаимно несовместимые комбинации флагов.
+func (o Options) Validate() error {
+	if o.OutputJSON && o.OutputCSV {
+		return fmt.Errorf("флаги -oJ и -oC взаимоисключающие")
+	}
+	return nil
+}
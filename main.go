@@ -2,17 +2,33 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"log"
-	"net"
 	"os"
-	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/miekg/dns"
 	"github.com/schollz/progressbar/v3"
+
+	"github.com/mazixs/Get_SubDomain/cli"
+	"github.com/mazixs/Get_SubDomain/output"
+	"github.com/mazixs/Get_SubDomain/permute"
+	"github.com/mazixs/Get_SubDomain/resolver"
+	"github.com/mazixs/Get_SubDomain/sources"
+	"github.com/mazixs/Get_SubDomain/takeover"
+	"github.com/mazixs/Get_SubDomain/wildcard"
+)
+
+// Сколько случайных меток зондировать при определении wildcard DNS и
+// какой они длины.
+const (
+	wildcardProbes   = 8
+	wildcardLabelLen = 10
 )
 
 // Функция для загрузки списка из файла
@@ -37,113 +53,281 @@ func loadFileToSlice(fileName string) ([]string, error) {
     return lines, nil
 }
 
-// Функция для проверки поддомена на наличие записей A или AAAA
-func checkSubdomain(subdomain string, nameservers []string, timeout time.Duration) bool {
-    c := new(dns.Client)
-    c.Timeout = timeout
+// loadLines читает непустые строки (без крайних пробелов) из r до конца
+// ввода.
+func loadLines(r *bufio.Scanner) []string {
+    var lines []string
+    for r.Scan() {
+        line := strings.TrimSpace(r.Text())
+        if line != "" {
+            lines = append(lines, line)
+        }
+    }
+    return lines
+}
 
-    m := new(dns.Msg)
-    m.SetQuestion(dns.Fqdn(subdomain), dns.TypeA)
+// candidate — поддомен-кандидат на резолвинг вместе с источником, из
+// которого он получен (для поля Source в output.Record).
+type candidate struct {
+    host   string
+    source string
+}
 
-    // Проверяем записи типа A
-    for _, ns := range nameservers {
-        ns = strings.TrimSpace(ns)
-        if ns == "" {
-            continue
-        }
-        nsAddr := net.JoinHostPort(ns, "53")
-        r, t, err := c.Exchange(m, nsAddr)
-        if err == nil && len(r.Answer) > 0 {
-            for _, ans := range r.Answer {
-                if _, ok := ans.(*dns.A); ok {
-                    return true
-                }
+// resolveSubdomain запрашивает A и AAAA записи host через pool, который
+// сам выбирает резолвер по здоровью и лимиту скорости (и может свободно
+// смешивать схемы udp://, tcp://, tls:// для DoT, https:// для DoH).
+func resolveSubdomain(host string, pool *resolver.ResolverPool, timeout time.Duration) output.Record {
+    rec := output.Record{Host: host}
+
+    m := new(dns.Msg)
+    m.SetQuestion(dns.Fqdn(host), dns.TypeA)
+    if r, ns, err := pool.Exchange(context.Background(), m, timeout); err == nil && r != nil {
+        for _, ans := range r.Answer {
+            if a, ok := ans.(*dns.A); ok {
+                rec.A = append(rec.A, a.A.String())
             }
         }
-        if t >= timeout {
-            continue // Пропускаем, если истек таймаут
+        if len(rec.A) > 0 {
+            rec.Resolver = ns.Raw
         }
     }
 
-    // Проверяем записи типа AAAA
-    m.SetQuestion(dns.Fqdn(subdomain), dns.TypeAAAA)
-    for _, ns := range nameservers {
-        ns = strings.TrimSpace(ns)
-        if ns == "" {
-            continue
-        }
-        nsAddr := net.JoinHostPort(ns, "53")
-        r, t, err := c.Exchange(m, nsAddr)
-        if err == nil && len(r.Answer) > 0 {
-            for _, ans := range r.Answer {
-                if _, ok := ans.(*dns.AAAA); ok {
-                    return true
-                }
+    m.SetQuestion(dns.Fqdn(host), dns.TypeAAAA)
+    if r, ns, err := pool.Exchange(context.Background(), m, timeout); err == nil && r != nil {
+        for _, ans := range r.Answer {
+            if aaaa, ok := ans.(*dns.AAAA); ok {
+                rec.AAAA = append(rec.AAAA, aaaa.AAAA.String())
             }
         }
-        if t >= timeout {
-            continue // Пропускаем, если истек таймаут
+        if len(rec.AAAA) > 0 && rec.Resolver == "" {
+            rec.Resolver = ns.Raw
         }
     }
 
-    return false
+    return rec
 }
 
-// Воркер для обработки поддоменов
-func worker(jobs <-chan string, results chan<- string, nameservers []string, timeout time.Duration, progressCh chan<- int) {
-    for subdomain := range jobs {
-        exists := checkSubdomain(subdomain, nameservers, timeout)
+// probeWildcardSignature зондирует wildcardProbes случайных
+// высокоэнтропийных меток домена и объединяет резолвящиеся из них A/AAAA
+// в сигнатуру wildcard DNS. Если ни одна метка не резолвится, wildcard не
+// обнаружен и возвращается пустая сигнатура.
+func probeWildcardSignature(domain string, pool *resolver.ResolverPool, timeout time.Duration) wildcard.Signature {
+    var a, aaaa []string
+    for i := 0; i < wildcardProbes; i++ {
+        probe := wildcard.RandomLabel(wildcardLabelLen) + "." + domain
+        rec := resolveSubdomain(probe, pool, timeout)
+        a = append(a, rec.A...)
+        aaaa = append(aaaa, rec.AAAA...)
+    }
+    return wildcard.NewSignature(a, aaaa)
+}
+
+// Воркер для обработки поддоменов. Если checkTakeover включён, для каждого
+// поддомена дополнительно проверяется CNAME на предмет захватываемого
+// стороннего сервиса.
+func worker(jobs <-chan candidate, results chan<- output.Record, takeovers chan<- takeover.Candidate, domain string, pool *resolver.ResolverPool, timeout time.Duration, checkTakeover bool, wildcards *wildcard.Detector, progressCh chan<- int) {
+    for job := range jobs {
+        rec := resolveSubdomain(job.host, pool, timeout)
+        rec.Domain = domain
+        rec.Source = job.source
+
+        exists := len(rec.A) > 0 || len(rec.AAAA) > 0
+        if checkTakeover {
+            if cname := takeover.LookupCNAME(job.host, pool, timeout); cname != "" {
+                rec.CNAME = cname
+                if c, ok := takeover.Check(job.host, cname); ok {
+                    takeovers <- c
+                }
+            }
+            progressCh <- 1
+        }
+
+        if exists && wildcards != nil && wildcards.IsWildcard(domain, rec.CNAME, rec.A, rec.AAAA) {
+            exists = false
+        }
+
         if exists {
-            results <- subdomain
+            results <- rec
         }
         progressCh <- 1
     }
 }
 
+// runPass прогоняет candidates через numWorkers воркеров для domain, пишет
+// результаты в writer (под resultsMu) и, если takeoversFile задан,
+// кандидатов на takeover — в него. Возвращает резолвящиеся хосты (для
+// последующей пермутации).
+func runPass(candidates []candidate, domain string, numWorkers int, pool *resolver.ResolverPool, timeout time.Duration, checkTakeover bool, wildcards *wildcard.Detector, writer output.Writer, resultsMu *sync.Mutex, takeoversFile *os.File, progressCh chan<- int) []string {
+    jobs := make(chan candidate, numWorkers)
+    results := make(chan output.Record, numWorkers)
+    takeovers := make(chan takeover.Candidate, numWorkers)
+    var wg sync.WaitGroup
+
+    for w := 0; w < numWorkers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            worker(jobs, results, takeovers, domain, pool, timeout, checkTakeover, wildcards, progressCh)
+        }()
+    }
+
+    var discovered []string
+    var discoveredMu sync.Mutex
+    resultWg := &sync.WaitGroup{}
+    resultWg.Add(1)
+    go func() {
+        defer resultWg.Done()
+        for rec := range results {
+            resultsMu.Lock()
+            err := writer.Write(rec)
+            resultsMu.Unlock()
+            if err != nil {
+                log.Printf("Ошибка записи результата для %s: %v", rec.Host, err)
+            }
+            discoveredMu.Lock()
+            discovered = append(discovered, rec.Host)
+            discoveredMu.Unlock()
+        }
+    }()
+
+    takeoversWg := &sync.WaitGroup{}
+    takeoversWg.Add(1)
+    go func() {
+        defer takeoversWg.Done()
+        if takeoversFile == nil {
+            for range takeovers {
+            }
+            return
+        }
+        for c := range takeovers {
+            fmt.Fprintf(takeoversFile, "%s\t%s\t%s\t%s\n", c.Subdomain, c.CNAME, c.Service, c.Evidence)
+        }
+    }()
+
+    go func() {
+        for _, c := range candidates {
+            jobs <- c
+        }
+        close(jobs)
+    }()
+
+    wg.Wait()
+    close(results)
+    close(takeovers)
+    resultWg.Wait()
+    takeoversWg.Wait()
+
+    return discovered
+}
+
 func main() {
-    domains, err := loadFileToSlice("domains.txt")
+    opts, err := cli.Parse()
     if err != nil {
-        log.Fatalf("Ошибка загрузки доменов: %v", err)
+        log.Fatalf("Ошибка разбора флагов: %v", err)
+    }
+    if err := opts.Validate(); err != nil {
+        log.Fatalf("Некорректные флаги: %v", err)
     }
 
-    subdomains, err := loadFileToSlice("subdomains.txt")
-    if err != nil {
-        log.Fatalf("Ошибка загрузки поддоменов: %v", err)
+    var domains []string
+    if len(opts.Domains) > 0 {
+        domains = opts.Domains
+    } else {
+        domains, err = loadFileToSlice(opts.DomainsFile)
+        if err != nil {
+            log.Fatalf("Ошибка загрузки доменов: %v", err)
+        }
+    }
+
+    var subdomains []string
+    if opts.Stdin {
+        subdomains = loadLines(bufio.NewScanner(os.Stdin))
+    } else {
+        subdomains, err = loadFileToSlice(opts.Wordlist)
+        if err != nil {
+            log.Fatalf("Ошибка загрузки поддоменов: %v", err)
+        }
     }
 
-    nameservers, err := loadFileToSlice("nameservers.txt")
-    if err != nil || len(nameservers) == 0 {
+    nameserverLines, err := loadFileToSlice(opts.Resolvers)
+    if err != nil || len(nameserverLines) == 0 {
         log.Fatalf("Ошибка загрузки DNS-серверов: %v", err)
     }
 
-    outputDir := "results"
-    if _, err := os.Stat(outputDir); os.IsNotExist(err) {
-        err := os.Mkdir(outputDir, 0755)
+    nameservers, err := resolver.ParseNameservers(nameserverLines)
+    if err != nil {
+        log.Fatalf("Ошибка разбора DNS-серверов: %v", err)
+    }
+
+    var passiveSources []sources.Source
+    if opts.Passive {
+        cfg, err := sources.LoadConfig("")
         if err != nil {
-            log.Fatalf("Не удалось создать директорию %s: %v", outputDir, err)
+            log.Fatalf("Ошибка загрузки конфига пассивных источников: %v", err)
         }
+        passiveSources = sources.Filter(sources.All(cfg), cli.SplitCSV(opts.Sources), cli.SplitCSV(opts.ExcludeSources))
+    }
+
+    writer, err := newWriter(opts)
+    if err != nil {
+        log.Fatalf("Ошибка настройки вывода: %v", err)
     }
+    defer writer.Close()
 
-    timeout := 2 * time.Second
-    totalChecks := len(domains) * len(subdomains)
+    timeout := opts.Timeout
+    ticksPerCandidate := 1
+    if opts.Takeover {
+        ticksPerCandidate = 2
+    }
+    var totalChecks int64 = int64(len(domains) * len(subdomains) * ticksPerCandidate)
     progressCh := make(chan int, 100)
+    maxCh := make(chan int64, 100)
 
-    bar := progressbar.NewOptions(totalChecks,
-        progressbar.OptionSetDescription("Проверка поддоменов..."),
-        progressbar.OptionSetWriter(os.Stderr),
-        progressbar.OptionShowCount(),
-        progressbar.OptionFullWidth(),
-    )
+    var bar *progressbar.ProgressBar
+    if opts.Silent {
+        bar = progressbar.NewOptions64(totalChecks, progressbar.OptionSetVisibility(false))
+    } else {
+        bar = progressbar.NewOptions64(totalChecks,
+            progressbar.OptionSetDescription("Проверка поддоменов..."),
+            progressbar.OptionSetWriter(os.Stderr),
+            progressbar.OptionShowCount(),
+            progressbar.OptionFullWidth(),
+        )
+    }
 
-    // Горутина для обновления прогрессбара
+    // Горутина для обновления прогрессбара. bar не потокобезопасен между
+    // Add и ChangeMax64 (ChangeMax64 не берёт внутреннюю блокировку), поэтому
+    // оба вызова должны идти из одной горутины — maxCh несёт поправки
+    // totalChecks от горутин по доменам вместо прямого вызова ChangeMax64.
     go func() {
-        for n := range progressCh {
-            bar.Add(n)
+        progress, max := progressCh, maxCh
+        for progress != nil || max != nil {
+            select {
+            case n, ok := <-progress:
+                if !ok {
+                    progress = nil
+                    continue
+                }
+                bar.Add(n)
+            case m, ok := <-max:
+                if !ok {
+                    max = nil
+                    continue
+                }
+                bar.ChangeMax64(m)
+            }
         }
     }()
 
-    numWorkers := runtime.NumCPU() * 10
+    numWorkers := opts.Threads
+    pool := resolver.NewResolverPool(nameservers, opts.RatePerResolver, opts.MaxRetries, timeout)
+
+    var wildcards *wildcard.Detector
+    if !opts.NoWildcardFilter {
+        wildcards = wildcard.NewDetector()
+    }
 
+    var resultsMu sync.Mutex
     var mainWg sync.WaitGroup
     for _, domain := range domains {
         domain = strings.TrimSpace(domain)
@@ -155,59 +339,129 @@ func main() {
         go func(domain string) {
             defer mainWg.Done()
 
-            jobs := make(chan string, numWorkers)
-            results := make(chan string, numWorkers)
-            var wg sync.WaitGroup
-
-            // Запуск воркеров
-            for w := 0; w < numWorkers; w++ {
-                wg.Add(1)
-                go func() {
-                    defer wg.Done()
-                    worker(jobs, results, nameservers, timeout, progressCh)
-                }()
+            if wildcards != nil {
+                wildcards.Set(domain, probeWildcardSignature(domain, pool, timeout))
             }
 
-            // Запуск горутины для записи результатов
-            outputFile := outputDir + "/" + domain + ".txt"
-            resultWg := &sync.WaitGroup{}
-            resultWg.Add(1)
-            go func() {
-                defer resultWg.Done()
-                file, err := os.Create(outputFile)
+            var takeoversFile *os.File
+            if opts.Takeover {
+                path := takeoverOutputPath(opts, domain)
+                file, err := os.Create(path)
                 if err != nil {
-                    log.Printf("Ошибка создания файла %s: %v", outputFile, err)
-                    return
+                    log.Printf("Ошибка создания файла %s: %v", path, err)
+                } else {
+                    takeoversFile = file
+                    defer file.Close()
                 }
-                defer file.Close()
+            }
 
-                for res := range results {
-                    fmt.Fprintln(file, res)
+            // Сбор кандидатов: активный перебор по словарю плюс, если
+            // включено, пассивные источники. Дедупликация — через sync.Map,
+            // которая также переживает пермутационный проход ниже.
+            var seen sync.Map
+            var candidates []candidate
+            for _, subdomain := range subdomains {
+                subdomain = strings.TrimSpace(subdomain)
+                if subdomain == "" {
+                    continue
+                }
+                fullSubdomain := subdomain + "." + domain
+                if _, loaded := seen.LoadOrStore(fullSubdomain, struct{}{}); !loaded {
+                    candidates = append(candidates, candidate{host: fullSubdomain, source: output.SourceBruteforce})
                 }
-            }()
-
-            // Добавление задач в канал jobs
-            go func() {
-                for _, subdomain := range subdomains {
-                    subdomain = strings.TrimSpace(subdomain)
-                    if subdomain == "" {
-                        continue
+            }
+            if passiveSources != nil {
+                for _, found := range sources.Run(context.Background(), passiveSources, domain) {
+                    if _, loaded := seen.LoadOrStore(found, struct{}{}); !loaded {
+                        candidates = append(candidates, candidate{host: found, source: output.SourcePassive})
                     }
-                    fullSubdomain := subdomain + "." + domain
-                    jobs <- fullSubdomain
                 }
-                close(jobs)
-            }()
+            }
+
+            // Поправляем общий счётчик прогрессбара на число пассивных
+            // кандидатов, не учтённых при первоначальном расчёте.
+            if extra := (len(candidates) - len(subdomains)) * ticksPerCandidate; extra != 0 {
+                maxCh <- atomic.AddInt64(&totalChecks, int64(extra))
+            }
+
+            discovered := runPass(candidates, domain, numWorkers, pool, timeout, opts.Takeover, wildcards, writer, &resultsMu, takeoversFile, progressCh)
 
-            // Ожидание завершения воркеров
-            wg.Wait()
-            close(results)
-            resultWg.Wait()
+            if opts.Permute {
+                var permCandidates []candidate
+                for _, host := range permute.Generate(domain, discovered, subdomains, opts.PermuteDepth) {
+                    if _, loaded := seen.LoadOrStore(host, struct{}{}); !loaded {
+                        permCandidates = append(permCandidates, candidate{host: host, source: output.SourceBruteforce})
+                    }
+                }
+                if extra := len(permCandidates) * ticksPerCandidate; extra != 0 {
+                    maxCh <- atomic.AddInt64(&totalChecks, int64(extra))
+                }
+                runPass(permCandidates, domain, numWorkers, pool, timeout, opts.Takeover, wildcards, writer, &resultsMu, takeoversFile, progressCh)
+            }
         }(domain)
     }
 
     mainWg.Wait()
     close(progressCh)
+    close(maxCh)
     bar.Finish()
-    fmt.Println("Проверка завершена.")
+    if !opts.Silent {
+        if wildcards != nil {
+            for _, line := range wildcards.Summary() {
+                fmt.Println(line)
+            }
+        }
+        printResolverStats(pool)
+        fmt.Println("Проверка завершена.")
+    }
+}
+
+// printResolverStats печатает по одной строке на резолвер: число
+// запросов, ошибок, среднюю задержку и число событий бана.
+func printResolverStats(pool *resolver.ResolverPool) {
+    for _, s := range pool.Stats() {
+        fmt.Printf(
+            "резолвер %s: запросов %d, ошибок %d, средняя задержка %s, банов %d\n",
+            s.Nameserver.Raw, s.Queries, s.Errors, s.AvgLatency, s.BenchEvents,
+        )
+    }
+}
+
+// newWriter выбирает формат вывода по флагам -oJ/-oC/-oD и открывает
+// назначение, заданное -o ("-" или пустая строка означают stdout, если
+// формат не -oD).
+func newWriter(opts cli.Options) (output.Writer, error) {
+    switch {
+    case opts.OutputJSON:
+        w, err := openOutput(opts.Output)
+        if err != nil {
+            return nil, err
+        }
+        return output.NewJSONWriter(w), nil
+    case opts.OutputCSV:
+        w, err := openOutput(opts.Output)
+        if err != nil {
+            return nil, err
+        }
+        return output.NewCSVWriter(w)
+    default:
+        return output.NewDirWriter(opts.Output)
+    }
+}
+
+func openOutput(path string) (io.WriteCloser, error) {
+    if path == "" || path == "-" {
+        return output.NopCloser(os.Stdout), nil
+    }
+    return os.Create(path)
+}
+
+// takeoverOutputPath строит путь к файлу с кандидатами на takeover. В
+// режиме -oD (по домену) он кладётся рядом с обычными результатами,
+// иначе — в текущую директорию под именем домена.
+func takeoverOutputPath(opts cli.Options, domain string) string {
+    if opts.OutputDir {
+        return opts.Output + "/" + domain + ".takeovers.txt"
+    }
+    return domain + ".takeovers.txt"
 }
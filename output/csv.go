@@ -0,0 +1,51 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"sync"
+)
+
+var csvHeader = []string{"host", "domain", "a", "aaaa", "cname", "source", "resolver"}
+
+// CSVWriter пишет результаты в CSV с заголовком csvHeader.
+type CSVWriter struct {
+	mu  sync.Mutex
+	w   *csv.Writer
+	out io.WriteCloser
+}
+
+// NewCSVWriter создаёт CSVWriter поверх w и сразу пишет заголовок.
+// w закрывается в Close.
+func NewCSVWriter(w io.WriteCloser) (*CSVWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return &CSVWriter{w: cw, out: w}, nil
+}
+
+func (w *CSVWriter) Write(r Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.w.Write([]string{
+		r.Host,
+		r.Domain,
+		strings.Join(r.A, ";"),
+		strings.Join(r.AAAA, ";"),
+		r.CNAME,
+		r.Source,
+		r.Resolver,
+	})
+	if err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *CSVWriter) Close() error {
+	return w.out.Close()
+}
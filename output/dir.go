@@ -0,0 +1,67 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// DirWriter воспроизводит поведение инструмента по умолчанию: один
+// текстовый файл "<domain>.txt" на домен внутри outputDir, одна строка —
+// один поддомен.
+type DirWriter struct {
+	dir string
+
+	mu    sync.Mutex
+	files map[string]*bufio.Writer
+	fhs   map[string]*os.File
+}
+
+// NewDirWriter создаёт DirWriter, создавая dir, если он не существует.
+func NewDirWriter(dir string) (*DirWriter, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return nil, fmt.Errorf("не удалось создать директорию %s: %w", dir, err)
+		}
+	}
+	return &DirWriter{
+		dir:   dir,
+		files: make(map[string]*bufio.Writer),
+		fhs:   make(map[string]*os.File),
+	}, nil
+}
+
+func (w *DirWriter) Write(r Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	buf, ok := w.files[r.Domain]
+	if !ok {
+		f, err := os.Create(w.dir + "/" + r.Domain + ".txt")
+		if err != nil {
+			return fmt.Errorf("не удалось создать файл для %s: %w", r.Domain, err)
+		}
+		buf = bufio.NewWriter(f)
+		w.files[r.Domain] = buf
+		w.fhs[r.Domain] = f
+	}
+
+	_, err := fmt.Fprintln(buf, r.Host)
+	return err
+}
+
+func (w *DirWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for domain, buf := range w.files {
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		if err := w.fhs[domain].Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
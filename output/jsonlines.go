@@ -0,0 +1,29 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONWriter пишет один JSON-объект на строку (JSON lines) в w.
+type JSONWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	out io.WriteCloser
+}
+
+// NewJSONWriter создаёт JSONWriter поверх w. w закрывается в Close.
+func NewJSONWriter(w io.WriteCloser) *JSONWriter {
+	return &JSONWriter{enc: json.NewEncoder(w), out: w}
+}
+
+func (w *JSONWriter) Write(r Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Encode(r)
+}
+
+func (w *JSONWriter) Close() error {
+	return w.out.Close()
+}
@@ -0,0 +1,13 @@
+package output
+
+import "io"
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// NopCloser wraps w so Close is a no-op — used for os.Stdout, which
+// callers must not actually close.
+func NopCloser(w io.Writer) io.WriteCloser {
+	return nopWriteCloser{w}
+}
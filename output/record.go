@@ -0,0 +1,28 @@
+// Package output форматирует результаты резолвинга поддоменов в разные
+// структурированные представления (директория текстовых файлов, JSON
+// lines, CSV) для дальнейшей обработки другими инструментами.
+package output
+
+// Record — один резолвящийся поддомен со всем, что о нём удалось узнать.
+type Record struct {
+	Host     string   `json:"host"`
+	Domain   string   `json:"domain"`
+	A        []string `json:"a,omitempty"`
+	AAAA     []string `json:"aaaa,omitempty"`
+	CNAME    string   `json:"cname,omitempty"`
+	Source   string   `json:"source"`
+	Resolver string   `json:"resolver,omitempty"`
+}
+
+// Источники, из которых мог прийти Record.
+const (
+	SourceBruteforce = "bruteforce"
+	SourcePassive    = "passive"
+)
+
+// Writer принимает резолвящиеся поддомены и сохраняет их в выбранном
+// формате. Write может вызываться из нескольких горутин одновременно.
+type Writer interface {
+	Write(Record) error
+	Close() error
+}
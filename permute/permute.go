@@ -0,0 +1,100 @@
+// Package permute генерирует поддомены-кандидаты altdns-образной
+// пермутацией уже найденных поддоменов: вставкой слов из словаря между
+// метками, перебором числовых суффиксов и заменой типовых меток
+// (dev/stage/prod) друг на друга.
+package permute
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericSuffix выделяет буквенную основу и числовой суффикс метки,
+// например "api1" -> "api", "1".
+var numericSuffix = regexp.MustCompile(`^(.+?)(\d+)$`)
+
+// envTokens — типовые метки окружений, которые altdns обычно переставляет
+// друг с другом (dev.example.com -> stage.example.com, prod.example.com).
+var envTokens = []string{"dev", "stage", "prod"}
+
+// Generate строит кандидатов для domain на основе уже найденных поддоменов
+// discovered (полные имена вида "api.dev.example.com") и слов из tokens.
+// depth ограничивает диапазон перебираемых числовых суффиксов (1..depth).
+// Результат не дедуплицирован против уже опрошенных имён — это забота
+// вызывающего кода (sync.Map на каждый домен).
+func Generate(domain string, discovered []string, tokens []string, depth int) []string {
+	var out []string
+	seen := make(map[string]struct{})
+	add := func(host string) {
+		if _, ok := seen[host]; ok {
+			return
+		}
+		seen[host] = struct{}{}
+		out = append(out, host)
+	}
+
+	suffix := "." + domain
+	for _, host := range discovered {
+		rel := strings.TrimSuffix(host, suffix)
+		if rel == host || rel == "" {
+			continue
+		}
+		labels := strings.Split(rel, ".")
+		label0 := labels[0]
+		rest := strings.Join(labels[1:], ".")
+
+		for _, token := range tokens {
+			token = strings.TrimSpace(token)
+			if token == "" {
+				continue
+			}
+			add(join(domain, rest, label0+"-"+token))
+			add(join(domain, rest, label0+"."+token))
+			add(join(domain, rest, token+"-"+label0))
+		}
+
+		if m := numericSuffix.FindStringSubmatch(label0); m != nil {
+			base := m[1]
+			for n := 1; n <= depth; n++ {
+				if newLabel := base + strconv.Itoa(n); newLabel != label0 {
+					add(join(domain, rest, newLabel))
+				}
+			}
+		}
+
+		for i, label := range labels {
+			for _, from := range envTokens {
+				if label != from {
+					continue
+				}
+				for _, to := range envTokens {
+					if to != from {
+						add(replaceLabel(domain, labels, i, to))
+					}
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// join собирает полное имя домена из новой первой метки, остальных меток
+// (может быть пустой строкой) и самого домена.
+func join(domain, rest, label0 string) string {
+	if rest == "" {
+		return label0 + "." + domain
+	}
+	return label0 + "." + rest + "." + domain
+}
+
+// replaceLabel собирает полное имя домена из labels с заменой метки по
+// индексу i на replacement, например (["app", "dev"], 1, "stage") ->
+// "app.stage.example.com".
+func replaceLabel(domain string, labels []string, i int, replacement string) string {
+	out := make([]string, len(labels))
+	copy(out, labels)
+	out[i] = replacement
+	return strings.Join(out, ".") + "." + domain
+}
@@ -0,0 +1,61 @@
+package permute
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGenerateTokenInsertion(t *testing.T) {
+	got := Generate("example.com", []string{"api.example.com"}, []string{"v2"}, 0)
+	want := []string{"api-v2.example.com", "api.v2.example.com", "v2-api.example.com"}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Generate() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateNumericSuffix(t *testing.T) {
+	got := Generate("example.com", []string{"api1.example.com"}, nil, 3)
+	want := []string{"api2.example.com", "api3.example.com"}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Generate() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateEnvTokenSwap(t *testing.T) {
+	got := Generate("example.com", []string{"dev.example.com"}, nil, 0)
+	want := []string{"stage.example.com", "prod.example.com"}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Generate() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateEnvTokenSwapNonLeadingLabel(t *testing.T) {
+	got := Generate("example.com", []string{"app.dev.example.com"}, nil, 0)
+	want := []string{"app.stage.example.com", "app.prod.example.com"}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Generate() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateDeduplicates(t *testing.T) {
+	got := Generate("example.com", []string{"api.example.com", "api.example.com"}, []string{"v2"}, 0)
+	if len(got) != 3 {
+		t.Fatalf("Generate() produced %d entries for duplicate input, want 3: %v", len(got), got)
+	}
+}
+
+func TestGenerateSkipsApexAndUnrelatedHosts(t *testing.T) {
+	got := Generate("example.com", []string{"example.com", "api.other.com"}, []string{"v2"}, 0)
+	if len(got) != 0 {
+		t.Fatalf("Generate() = %v, want no candidates for apex/unrelated input", got)
+	}
+}
@@ -0,0 +1,231 @@
+package resolver
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/time/rate"
+)
+
+// ewmaAlpha — вес последнего наблюдения в экспоненциально взвешенном
+// скользящем среднем задержки резолвера.
+const ewmaAlpha = 0.3
+
+// benchBase — базовая длительность бана резолвера после ошибки; растёт
+// экспоненциально с общим числом банов резолвера (не более чем в 32 раза),
+// см. resolverState.recordFailure.
+const benchBase = 5 * time.Second
+
+// resolverState — счётчики здоровья одного резолвера: успехи/ошибки,
+// EWMA задержки и текущий бан.
+type resolverState struct {
+	limiter *rate.Limiter
+
+	mu           sync.Mutex
+	successes    int64
+	errors       int64
+	ewmaLatency  time.Duration
+	benchedUntil time.Time
+	benchEvents  int64
+}
+
+func newResolverState(ratePerResolver float64) *resolverState {
+	limit := rate.Limit(ratePerResolver)
+	if ratePerResolver <= 0 {
+		limit = rate.Inf
+	}
+	return &resolverState{limiter: rate.NewLimiter(limit, 1)}
+}
+
+// score — вес резолвера при взвешенном случайном выборе: доля успешных
+// запросов со сглаживанием Лапласа, чтобы новый резолвер начинал на
+// равных, а не с нулевым весом.
+func (s *resolverState) score() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return float64(s.successes+1) / float64(s.successes+s.errors+2)
+}
+
+func (s *resolverState) benched(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.benchedUntil.After(now)
+}
+
+func (s *resolverState) recordSuccess(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.successes++
+	if s.ewmaLatency == 0 {
+		s.ewmaLatency = latency
+	} else {
+		s.ewmaLatency = time.Duration(ewmaAlpha*float64(latency) + (1-ewmaAlpha)*float64(s.ewmaLatency))
+	}
+}
+
+func (s *resolverState) recordFailure() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors++
+	s.benchEvents++
+	bench := benchBase << min(s.benchEvents-1, 5)
+	s.benchedUntil = time.Now().Add(bench)
+}
+
+// Stats — снимок счётчиков одного резолвера для итоговой сводки.
+type Stats struct {
+	Nameserver  Nameserver
+	Queries     int64
+	Errors      int64
+	AvgLatency  time.Duration
+	BenchEvents int64
+}
+
+// ResolverPool выбирает резолвер из nameservers по истории успехов/ошибок
+// и ждёт его ограничения скорости (токен-бакет на резолвер): здоровые
+// резолверы получают больше запросов, а те, что недавно ответили
+// SERVFAIL/REFUSED или превысили таймаут, временно банятся. EWMA задержки
+// собирается только для итоговой статистики и на выбор не влияет.
+type ResolverPool struct {
+	transport   *Pool
+	nameservers []Nameserver
+	states      []*resolverState
+	maxRetries  int
+}
+
+// NewResolverPool создаёт ResolverPool. ratePerResolver — лимит запросов
+// в секунду на резолвер (0 или отрицательное значение — без ограничения).
+// maxRetries — сколько раз повторить запрос на другом резолвере при
+// ошибке или неудачном Rcode.
+func NewResolverPool(nameservers []Nameserver, ratePerResolver float64, maxRetries int, timeout time.Duration) *ResolverPool {
+	states := make([]*resolverState, len(nameservers))
+	for i := range nameservers {
+		states[i] = newResolverState(ratePerResolver)
+	}
+	return &ResolverPool{
+		transport:   NewPool(timeout),
+		nameservers: nameservers,
+		states:      states,
+		maxRetries:  maxRetries,
+	}
+}
+
+// Exchange отправляет m через резолвер, выбранный по весу здоровья, ждёт
+// его лимит скорости и при ошибке/SERVFAIL/REFUSED повторяет через другой
+// резолвер до maxRetries раз. Возвращает ответ и резолвер, который его дал.
+func (p *ResolverPool) Exchange(ctx context.Context, m *dns.Msg, timeout time.Duration) (*dns.Msg, Nameserver, error) {
+	var lastErr error
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		idx := p.pick()
+		ns := p.nameservers[idx]
+		st := p.states[idx]
+
+		if err := st.limiter.Wait(ctx); err != nil {
+			return nil, Nameserver{}, err
+		}
+
+		start := time.Now()
+		r, err := p.transport.Exchange(ns, m, timeout)
+		latency := time.Since(start)
+
+		if err != nil || r == nil || isFailureRcode(r.Rcode) {
+			st.recordFailure()
+			lastErr = err
+			if lastErr == nil {
+				if r != nil {
+					lastErr = &RcodeError{Nameserver: ns.Raw, Rcode: r.Rcode}
+				} else {
+					lastErr = &RcodeError{Nameserver: ns.Raw, Rcode: dns.RcodeServerFailure}
+				}
+			}
+			continue
+		}
+
+		st.recordSuccess(latency)
+		return r, ns, nil
+	}
+	return nil, Nameserver{}, lastErr
+}
+
+func isFailureRcode(rcode int) bool {
+	return rcode == dns.RcodeServerFailure || rcode == dns.RcodeRefused
+}
+
+// pick выбирает индекс резолвера взвешенным случайным выбором по
+// здоровью среди небанённых; если все забанены, выбирает наименее плохой.
+func (p *ResolverPool) pick() int {
+	now := time.Now()
+
+	var candidates []int
+	var weights []float64
+	total := 0.0
+	for i, st := range p.states {
+		if st.benched(now) {
+			continue
+		}
+		w := st.score()
+		candidates = append(candidates, i)
+		weights = append(weights, w)
+		total += w
+	}
+
+	if len(candidates) == 0 {
+		return p.leastRecentlyBenched()
+	}
+
+	r := rand.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r <= 0 {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+func (p *ResolverPool) leastRecentlyBenched() int {
+	best := 0
+	var bestUntil time.Time
+	for i, st := range p.states {
+		st.mu.Lock()
+		until := st.benchedUntil
+		st.mu.Unlock()
+		if i == 0 || until.Before(bestUntil) {
+			best = i
+			bestUntil = until
+		}
+	}
+	return best
+}
+
+// Stats возвращает снимок статистики по каждому резолверу в исходном
+// порядке nameservers — для итоговой сводки по завершении работы.
+func (p *ResolverPool) Stats() []Stats {
+	stats := make([]Stats, len(p.nameservers))
+	for i, st := range p.states {
+		st.mu.Lock()
+		stats[i] = Stats{
+			Nameserver:  p.nameservers[i],
+			Queries:     st.successes + st.errors,
+			Errors:      st.errors,
+			AvgLatency:  st.ewmaLatency,
+			BenchEvents: st.benchEvents,
+		}
+		st.mu.Unlock()
+	}
+	return stats
+}
+
+// RcodeError сообщает, что резолвер ответил кодом, который считается
+// неудачей (SERVFAIL, REFUSED), хотя транспорт не вернул ошибку.
+type RcodeError struct {
+	Nameserver string
+	Rcode      int
+}
+
+func (e *RcodeError) Error() string {
+	return "резолвер " + e.Nameserver + " ответил " + dns.RcodeToString[e.Rcode]
+}
@@ -0,0 +1,98 @@
+package resolver
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Pool хранит переиспользуемые клиенты на каждую транспортную схему, чтобы
+// DoT/DoH-соединения не пересоздавались на каждый запрос.
+type Pool struct {
+	mu      sync.Mutex
+	clients map[string]*dns.Client
+	http    *http.Client
+}
+
+// NewPool создаёт пул клиентов с заданным таймаутом запроса.
+func NewPool(timeout time.Duration) *Pool {
+	return &Pool{
+		clients: make(map[string]*dns.Client),
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *Pool) dnsClient(scheme string, timeout time.Duration) *dns.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[scheme]; ok {
+		return c
+	}
+
+	c := &dns.Client{Timeout: timeout}
+	switch scheme {
+	case SchemeTCP:
+		c.Net = "tcp"
+	case SchemeTLS:
+		c.Net = "tcp-tls"
+		c.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	p.clients[scheme] = c
+	return c
+}
+
+// Exchange отправляет m резолверу ns через транспорт, соответствующий его
+// схеме, и возвращает ответ.
+func (p *Pool) Exchange(ns Nameserver, m *dns.Msg, timeout time.Duration) (*dns.Msg, error) {
+	if ns.Scheme == SchemeHTTPS {
+		return p.exchangeDoH(ns, m, timeout)
+	}
+
+	c := p.dnsClient(ns.Scheme, timeout)
+	r, _, err := c.Exchange(m, ns.Addr)
+	return r, err
+}
+
+// exchangeDoH отправляет запрос в формате application/dns-message по HTTPS,
+// как описано в RFC 8484.
+func (p *Pool) exchangeDoH(ns Nameserver, m *dns.Msg, timeout time.Duration) (*dns.Msg, error) {
+	packed, err := m.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("упаковка DNS-сообщения: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ns.Addr, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH-резолвер %s вернул статус %d", ns.Addr, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(dns.Msg)
+	if err := r.Unpack(body); err != nil {
+		return nil, fmt.Errorf("распаковка ответа DoH: %w", err)
+	}
+	return r, nil
+}
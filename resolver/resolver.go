@@ -0,0 +1,80 @@
+// Package resolver разбирает адреса DNS-резолверов и выполняет запросы
+// через подходящий транспорт: обычный UDP/TCP, DoT (tls://) или DoH (https://).
+package resolver
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Поддерживаемые схемы в nameservers.txt.
+const (
+	SchemeUDP   = "udp"
+	SchemeTCP   = "tcp"
+	SchemeTLS   = "tls"   // DNS-over-TLS
+	SchemeHTTPS = "https" // DNS-over-HTTPS
+)
+
+// Nameserver — разобранный адрес резолвера из nameservers.txt.
+type Nameserver struct {
+	Scheme string
+	Addr   string // host:port для udp/tcp/tls, полный URL для https
+	Raw    string
+}
+
+// ParseNameserver разбирает одну строку nameservers.txt. Строки без схемы
+// (например "8.8.8.8") трактуются как обычный UDP-резолвер на порту 53,
+// чтобы существующие списки продолжали работать без изменений.
+func ParseNameserver(raw string) (Nameserver, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Nameserver{}, fmt.Errorf("пустой адрес резолвера")
+	}
+
+	if !strings.Contains(raw, "://") {
+		return Nameserver{Scheme: SchemeUDP, Addr: net.JoinHostPort(raw, "53"), Raw: raw}, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Nameserver{}, fmt.Errorf("не удалось разобрать адрес резолвера %q: %w", raw, err)
+	}
+
+	switch u.Scheme {
+	case SchemeUDP, SchemeTCP:
+		addr := u.Host
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "53")
+		}
+		return Nameserver{Scheme: u.Scheme, Addr: addr, Raw: raw}, nil
+	case SchemeTLS:
+		addr := u.Host
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "853")
+		}
+		return Nameserver{Scheme: u.Scheme, Addr: addr, Raw: raw}, nil
+	case SchemeHTTPS:
+		return Nameserver{Scheme: u.Scheme, Addr: raw, Raw: raw}, nil
+	default:
+		return Nameserver{}, fmt.Errorf("неизвестная схема резолвера %q", u.Scheme)
+	}
+}
+
+// ParseNameservers разбирает список строк, пропуская пустые.
+func ParseNameservers(raw []string) ([]Nameserver, error) {
+	nameservers := make([]Nameserver, 0, len(raw))
+	for _, line := range raw {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		ns, err := ParseNameserver(line)
+		if err != nil {
+			return nil, err
+		}
+		nameservers = append(nameservers, ns)
+	}
+	return nameservers, nil
+}
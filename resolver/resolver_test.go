@@ -0,0 +1,82 @@
+package resolver
+
+import "testing"
+
+func TestParseNameserver(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    Nameserver
+		wantErr bool
+	}{
+		{
+			name: "bare address defaults to udp/53",
+			raw:  "8.8.8.8",
+			want: Nameserver{Scheme: SchemeUDP, Addr: "8.8.8.8:53", Raw: "8.8.8.8"},
+		},
+		{
+			name: "udp scheme with explicit port",
+			raw:  "udp://8.8.8.8:5353",
+			want: Nameserver{Scheme: SchemeUDP, Addr: "8.8.8.8:5353", Raw: "udp://8.8.8.8:5353"},
+		},
+		{
+			name: "tcp scheme without port defaults to 53",
+			raw:  "tcp://8.8.8.8",
+			want: Nameserver{Scheme: SchemeTCP, Addr: "8.8.8.8:53", Raw: "tcp://8.8.8.8"},
+		},
+		{
+			name: "tls scheme without port defaults to 853",
+			raw:  "tls://1.1.1.1",
+			want: Nameserver{Scheme: SchemeTLS, Addr: "1.1.1.1:853", Raw: "tls://1.1.1.1"},
+		},
+		{
+			name: "https scheme keeps the full url as addr",
+			raw:  "https://1.1.1.1/dns-query",
+			want: Nameserver{Scheme: SchemeHTTPS, Addr: "https://1.1.1.1/dns-query", Raw: "https://1.1.1.1/dns-query"},
+		},
+		{
+			name:    "empty address is an error",
+			raw:     "   ",
+			wantErr: true,
+		},
+		{
+			name:    "unknown scheme is an error",
+			raw:     "ftp://1.1.1.1",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseNameserver(tc.raw)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseNameserver(%q) = %+v, nil; want error", tc.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNameserver(%q) returned unexpected error: %v", tc.raw, err)
+			}
+			if got != tc.want {
+				t.Fatalf("ParseNameserver(%q) = %+v, want %+v", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseNameservers(t *testing.T) {
+	got, err := ParseNameservers([]string{"8.8.8.8", "", "  ", "1.1.1.1"})
+	if err != nil {
+		t.Fatalf("ParseNameservers() returned unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ParseNameservers() = %v, want 2 entries (blank lines skipped)", got)
+	}
+}
+
+func TestParseNameserversPropagatesError(t *testing.T) {
+	if _, err := ParseNameservers([]string{"ftp://1.1.1.1"}); err == nil {
+		t.Fatal("ParseNameservers() should propagate a parse error from a bad entry")
+	}
+}
@@ -0,0 +1,67 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AlienVaultOTX запрашивает пассивный DNS из AlienVault Open Threat
+// Exchange. APIKey не обязателен — без него действуют более жёсткие
+// лимиты запросов.
+type AlienVaultOTX struct {
+	APIKey string
+}
+
+// NewAlienVaultOTX создаёт источник OTX с опциональным API-ключом.
+func NewAlienVaultOTX(apiKey string) *AlienVaultOTX { return &AlienVaultOTX{APIKey: apiKey} }
+
+func (a *AlienVaultOTX) Name() string { return "alienvault" }
+
+type otxResponse struct {
+	PassiveDNS []struct {
+		Hostname string `json:"hostname"`
+	} `json:"passive_dns"`
+}
+
+func (a *AlienVaultOTX) Run(ctx context.Context, domain string) <-chan Result {
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+
+		url := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/passive_dns", domain)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return
+		}
+		if a.APIKey != "" {
+			req.Header.Set("X-OTX-API-KEY", a.APIKey)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+
+		var parsed otxResponse
+		if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+			return
+		}
+
+		for _, entry := range parsed.PassiveDNS {
+			host := strings.ToLower(strings.TrimSpace(entry.Hostname))
+			if host == "" || !strings.HasSuffix(host, "."+domain) && host != domain {
+				continue
+			}
+			select {
+			case out <- Result{Subdomain: host, Source: a.Name()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,43 @@
+package sources
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config хранит учётные данные для источников пассивной разведки,
+// загружаемые из ~/.config/get_subdomain/config.yaml.
+type Config struct {
+	AlienVaultAPIKey string `yaml:"alienvault_api_key"`
+}
+
+// LoadConfig читает конфиг пассивных источников из path. Если path пуст,
+// используется путь по умолчанию ~/.config/get_subdomain/config.yaml.
+// Отсутствующий файл не является ошибкой — источники без ключей просто
+// продолжат работать анонимно.
+func LoadConfig(path string) (Config, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Config{}, fmt.Errorf("не удалось определить домашнюю директорию: %w", err)
+		}
+		path = filepath.Join(home, ".config", "get_subdomain", "config.yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("чтение конфига %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("разбор конфига %s: %w", path, err)
+	}
+	return cfg, nil
+}
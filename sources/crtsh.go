@@ -0,0 +1,61 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CrtSh запрашивает сертификаты Certificate Transparency через crt.sh.
+type CrtSh struct{}
+
+// NewCrtSh создаёт источник crt.sh.
+func NewCrtSh() *CrtSh { return &CrtSh{} }
+
+func (c *CrtSh) Name() string { return "crtsh" }
+
+type crtShEntry struct {
+	NameValue string `json:"name_value"`
+}
+
+func (c *CrtSh) Run(ctx context.Context, domain string) <-chan Result {
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+
+		url := fmt.Sprintf("https://crt.sh/?q=%%25.%s&output=json", domain)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+
+		var entries []crtShEntry
+		if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+			return
+		}
+
+		for _, entry := range entries {
+			for _, name := range strings.Split(entry.NameValue, "\n") {
+				name = strings.ToLower(strings.TrimSpace(name))
+				name = strings.TrimPrefix(name, "*.")
+				if name == "" || !strings.HasSuffix(name, "."+domain) && name != domain {
+					continue
+				}
+				select {
+				case out <- Result{Subdomain: name, Source: c.Name()}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,52 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HackerTarget запрашивает бесплатный API hackertarget.com/hostsearch.
+type HackerTarget struct{}
+
+// NewHackerTarget создаёт источник HackerTarget.
+func NewHackerTarget() *HackerTarget { return &HackerTarget{} }
+
+func (h *HackerTarget) Name() string { return "hackertarget" }
+
+func (h *HackerTarget) Run(ctx context.Context, domain string) <-chan Result {
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+
+		url := fmt.Sprintf("https://api.hackertarget.com/hostsearch/?q=%s", domain)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			// Формат ответа: "host,ip" по одной записи на строку.
+			line := scanner.Text()
+			host := strings.ToLower(strings.TrimSpace(strings.SplitN(line, ",", 2)[0]))
+			if host == "" || !strings.HasSuffix(host, "."+domain) && host != domain {
+				continue
+			}
+			select {
+			case out <- Result{Subdomain: host, Source: h.Name()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,33 @@
+package sources
+
+import (
+	"context"
+	"sync"
+)
+
+// Run запускает src параллельно для domain и возвращает объединённый,
+// дедуплицированный (через sync.Map) список найденных поддоменов.
+func Run(ctx context.Context, src []Source, domain string) []string {
+	var seen sync.Map
+	var mu sync.Mutex
+	var unique []string
+
+	var wg sync.WaitGroup
+	for _, s := range src {
+		wg.Add(1)
+		go func(s Source) {
+			defer wg.Done()
+			for res := range s.Run(ctx, domain) {
+				if _, loaded := seen.LoadOrStore(res.Subdomain, struct{}{}); loaded {
+					continue
+				}
+				mu.Lock()
+				unique = append(unique, res.Subdomain)
+				mu.Unlock()
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	return unique
+}
@@ -0,0 +1,58 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RapidDNS извлекает поддомены из HTML-таблицы rapiddns.io (подзапросы
+// subdomain#).
+type RapidDNS struct{}
+
+// NewRapidDNS создаёт источник RapidDNS.
+func NewRapidDNS() *RapidDNS { return &RapidDNS{} }
+
+func (r *RapidDNS) Name() string { return "rapiddns" }
+
+var rapidDNSRowRe = regexp.MustCompile(`<td>([a-zA-Z0-9_.-]+)</td>`)
+
+func (r *RapidDNS) Run(ctx context.Context, domain string) <-chan Result {
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+
+		url := fmt.Sprintf("https://rapiddns.io/subdomain/%s?full=1", domain)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		seen := make(map[string]struct{})
+		for scanner.Scan() {
+			for _, match := range rapidDNSRowRe.FindAllStringSubmatch(scanner.Text(), -1) {
+				host := strings.ToLower(match[1])
+				if _, ok := seen[host]; ok || !strings.HasSuffix(host, "."+domain) && host != domain {
+					continue
+				}
+				seen[host] = struct{}{}
+				select {
+				case out <- Result{Subdomain: host, Source: r.Name()}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,64 @@
+// Package sources собирает поддомены из открытых сторонних источников
+// (пассивная разведка) — в дополнение к активному перебору по словарю.
+package sources
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Result — один поддомен, найденный источником.
+type Result struct {
+	Subdomain string
+	Source    string
+}
+
+// Source — источник пассивной разведки. Run должен закрыть возвращаемый
+// канал по завершении или при отмене ctx.
+type Source interface {
+	Name() string
+	Run(ctx context.Context, domain string) <-chan Result
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// All возвращает все встроенные источники в фиксированном порядке.
+func All(cfg Config) []Source {
+	return []Source{
+		NewCrtSh(),
+		NewHackerTarget(),
+		NewAlienVaultOTX(cfg.AlienVaultAPIKey),
+		NewRapidDNS(),
+		NewWaybackMachine(),
+	}
+}
+
+// Filter оставляет из sources только те, чьи имена перечислены в include
+// (если include не пуст), и убирает те, что перечислены в exclude.
+func Filter(all []Source, include, exclude []string) []Source {
+	includeSet := toSet(include)
+	excludeSet := toSet(exclude)
+
+	filtered := make([]Source, 0, len(all))
+	for _, s := range all {
+		if len(includeSet) > 0 {
+			if _, ok := includeSet[s.Name()]; !ok {
+				continue
+			}
+		}
+		if _, ok := excludeSet[s.Name()]; ok {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
+func toSet(names []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		set[n] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,64 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WaybackMachine извлекает хосты из архивированных URL через CDX API
+// web.archive.org.
+type WaybackMachine struct{}
+
+// NewWaybackMachine создаёт источник Wayback Machine.
+func NewWaybackMachine() *WaybackMachine { return &WaybackMachine{} }
+
+func (w *WaybackMachine) Name() string { return "wayback" }
+
+func (w *WaybackMachine) Run(ctx context.Context, domain string) <-chan Result {
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+
+		cdxURL := fmt.Sprintf(
+			"https://web.archive.org/cdx/search/cdx?url=*.%s/*&output=text&fl=original&collapse=urlkey",
+			domain,
+		)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, cdxURL, nil)
+		if err != nil {
+			return
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+
+		seen := make(map[string]struct{})
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			u, err := url.Parse(scanner.Text())
+			if err != nil {
+				continue
+			}
+			host := strings.ToLower(u.Hostname())
+			if host == "" || !strings.HasSuffix(host, "."+domain) && host != domain {
+				continue
+			}
+			if _, ok := seen[host]; ok {
+				continue
+			}
+			seen[host] = struct{}{}
+			select {
+			case out <- Result{Subdomain: host, Source: w.Name()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
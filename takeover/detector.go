@@ -0,0 +1,85 @@
+package takeover
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/mazixs/Get_SubDomain/resolver"
+)
+
+// Candidate — поддомен с висячим CNAME на известный сервис.
+type Candidate struct {
+	Subdomain string
+	CNAME     string
+	Service   string
+	Evidence  string
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// LookupCNAME запрашивает CNAME-запись subdomain через pool. Возвращает
+// пустую строку, если записи нет.
+func LookupCNAME(subdomain string, pool *resolver.ResolverPool, timeout time.Duration) string {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(subdomain), dns.TypeCNAME)
+
+	r, _, err := pool.Exchange(context.Background(), m, timeout)
+	if err != nil || r == nil {
+		return ""
+	}
+	for _, ans := range r.Answer {
+		if cname, ok := ans.(*dns.CNAME); ok {
+			return strings.TrimSuffix(cname.Target, ".")
+		}
+	}
+	return ""
+}
+
+// Check сопоставляет cname с таблицей отпечатков и, если сервис опознан,
+// подтверждает захватываемость запросом HTTP(S) GET к subdomain. Возвращает
+// Candidate и true, если страница содержит сигнатуру "ресурс не занят".
+func Check(subdomain, cname string) (Candidate, bool) {
+	fp, ok := Match(cname)
+	if !ok {
+		return Candidate{}, false
+	}
+
+	body, err := fetchBody(subdomain)
+	if err != nil {
+		return Candidate{}, false
+	}
+
+	found := strings.Contains(body, fp.BodySignature)
+	if found != fp.VulnerableWhen {
+		return Candidate{}, false
+	}
+
+	return Candidate{
+		Subdomain: subdomain,
+		CNAME:     cname,
+		Service:   fp.Service,
+		Evidence:  fp.BodySignature,
+	}, true
+}
+
+func fetchBody(subdomain string) (string, error) {
+	for _, scheme := range []string{"https", "http"} {
+		resp, err := httpClient.Get(fmt.Sprintf("%s://%s/", scheme, subdomain))
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+		return string(body), nil
+	}
+	return "", fmt.Errorf("не удалось получить страницу %s ни по https, ни по http", subdomain)
+}
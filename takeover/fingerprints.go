@@ -0,0 +1,72 @@
+// Package takeover обнаруживает поддомены, уязвимые к захвату (subdomain
+// takeover) через висячие CNAME-записи на неиспользуемые сервисы.
+package takeover
+
+import "strings"
+
+// Fingerprint описывает один сервис, подверженный takeover: по какому
+// суффиксу CNAME его узнать и каким телом ответа подтвердить, что ресурс
+// действительно не занят (и потому доступен для захвата).
+type Fingerprint struct {
+	Service        string
+	CNAMESuffixes  []string
+	BodySignature  string
+	VulnerableWhen bool // true, если совпадение BodySignature означает "не занято" (захватываемо)
+}
+
+// Fingerprints — встроенная таблица известных сервисов. Список не
+// претендует на полноту — добавляйте записи по мере появления новых
+// провайдеров.
+var Fingerprints = []Fingerprint{
+	{
+		Service:        "GitHub Pages",
+		CNAMESuffixes:  []string{"github.io", "github.map.fastly.net"},
+		BodySignature:  "There isn't a GitHub Pages site here",
+		VulnerableWhen: true,
+	},
+	{
+		Service:        "Amazon S3",
+		CNAMESuffixes:  []string{"s3.amazonaws.com", "s3-website"},
+		BodySignature:  "The specified bucket does not exist",
+		VulnerableWhen: true,
+	},
+	{
+		Service:        "Heroku",
+		CNAMESuffixes:  []string{"herokuapp.com", "herokudns.com", "herokussl.com"},
+		BodySignature:  "There's nothing here, yet",
+		VulnerableWhen: true,
+	},
+	{
+		Service:        "Microsoft Azure",
+		CNAMESuffixes:  []string{"azurewebsites.net", "cloudapp.net", "cloudapp.azure.com", "blob.core.windows.net"},
+		BodySignature:  "404 Web Site not found",
+		VulnerableWhen: true,
+	},
+	{
+		Service:        "Shopify",
+		CNAMESuffixes:  []string{"myshopify.com"},
+		BodySignature:  "Sorry, this shop is currently unavailable",
+		VulnerableWhen: true,
+	},
+	{
+		Service:        "Fastly",
+		CNAMESuffixes:  []string{"fastly.net"},
+		BodySignature:  "Fastly error: unknown domain",
+		VulnerableWhen: true,
+	},
+}
+
+// Match сопоставляет cname с известным сервисом по суффиксу. Возвращает
+// нулевое значение и false, если сервис не опознан.
+func Match(cname string) (Fingerprint, bool) {
+	cname = strings.ToLower(strings.TrimSuffix(cname, "."))
+	for _, fp := range Fingerprints {
+		for _, suffix := range fp.CNAMESuffixes {
+			suffix = strings.ToLower(suffix)
+			if strings.HasSuffix(cname, "."+suffix) || cname == suffix {
+				return fp, true
+			}
+		}
+	}
+	return Fingerprint{}, false
+}
@@ -0,0 +1,65 @@
+package wildcard
+
+import "testing"
+
+func TestSignatureEmpty(t *testing.T) {
+	if !(Signature{}).Empty() {
+		t.Fatal("zero-value Signature should be empty")
+	}
+	if NewSignature(nil, nil).Empty() != true {
+		t.Fatal("NewSignature(nil, nil) should be empty")
+	}
+	if NewSignature([]string{"1.2.3.4"}, nil).Empty() {
+		t.Fatal("NewSignature with an address should not be empty")
+	}
+}
+
+func TestSignatureMatches(t *testing.T) {
+	sig := NewSignature([]string{"1.2.3.4", "1.2.3.4", "5.6.7.8"}, nil)
+
+	if sig.Matches([]string{"5.6.7.8", "1.2.3.4"}, nil) != true {
+		t.Fatal("Matches should ignore order and duplicates")
+	}
+	if sig.Matches([]string{"1.2.3.4"}, nil) {
+		t.Fatal("Matches should reject a subset of the signature")
+	}
+	if sig.Matches(nil, nil) {
+		t.Fatal("Matches should reject an empty candidate")
+	}
+	if (Signature{}).Matches([]string{"1.2.3.4"}, nil) {
+		t.Fatal("an empty signature should never match")
+	}
+}
+
+func TestDetectorIsWildcard(t *testing.T) {
+	d := NewDetector()
+	d.Set("example.com", NewSignature([]string{"1.2.3.4"}, nil))
+
+	if !d.IsWildcard("example.com", "", []string{"1.2.3.4"}, nil) {
+		t.Fatal("candidate matching the wildcard signature should be filtered")
+	}
+	if d.IsWildcard("example.com", "", []string{"9.9.9.9"}, nil) {
+		t.Fatal("candidate with different addresses should not be filtered")
+	}
+	if d.IsWildcard("example.com", "cname.example.net", []string{"1.2.3.4"}, nil) {
+		t.Fatal("candidate with its own CNAME should not be filtered as wildcard noise")
+	}
+	if d.IsWildcard("unknown.com", "", []string{"1.2.3.4"}, nil) {
+		t.Fatal("domain without a recorded signature should not be filtered")
+	}
+}
+
+func TestDetectorSummary(t *testing.T) {
+	d := NewDetector()
+	d.Set("example.com", NewSignature([]string{"1.2.3.4"}, nil))
+	d.Set("noWildcard.com", Signature{})
+
+	d.IsWildcard("example.com", "", []string{"1.2.3.4"}, nil)
+	d.IsWildcard("example.com", "", []string{"1.2.3.4"}, nil)
+
+	got := d.Summary()
+	want := []string{"wildcard detected for example.com, filtered 2 results"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("Summary() = %v, want %v", got, want)
+	}
+}